@@ -1,6 +1,7 @@
 package main
 
 import (
+	"archive/tar"
 	"context"
 	"fmt"
 	"io"
@@ -8,24 +9,42 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"runtime"
-	"time"
+	"sync"
 	"math"
+	"time"
 	"encoding/json"
 
+	"github.com/Masterminds/semver"
 	"github.com/containers/image/copy"
 	"github.com/containers/image/directory"
 	"github.com/containers/image/docker"
-//	"github.com/containers/image/manifest"
+	dockerarchive "github.com/containers/image/docker/archive"
+	dockerreference "github.com/containers/image/docker/reference"
+	"github.com/containers/image/image"
+	"github.com/containers/image/manifest"
+	ociarchive "github.com/containers/image/oci/archive"
+	ocilayout "github.com/containers/image/oci/layout"
 	"github.com/containers/image/transports"
 	"github.com/containers/image/types"
 	"github.com/containers/image/signature"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
 )
 
+// inspectCache memoizes types.ImageInspectInfo per fully-qualified tag
+// ("docker://host/repo:tag"), so resolving tags-last's "created" timestamps
+// during tag selection doesn't force copyImageTag to re-inspect the same
+// tag a second time.
+var inspectCache sync.Map
+
 var MAX_THREADS int = int(math.Min(float64(runtime.NumCPU()), 6.0))
 
 type registrySyncOptions struct {
@@ -35,6 +54,11 @@ type registrySyncOptions struct {
 	removeSignatures  bool   // Do not copy signatures from the source image
 	signByFingerprint string // Sign the image using a GPG key with the specified fingerprint
 	sourceYaml        bool
+	platforms         string // Comma-separated os/arch[/variant] list to keep from a multi-arch manifest list
+	maxParallelCopies     int  // Bound on concurrent tag copies
+	maxParallelTagLookups int  // Bound on concurrent per-repo tag lookups when reading --source-yaml
+	failFast              bool // Abort on the first tag failure instead of aggregating errors
+	dryRun                bool // Report what would change without copying anything
 }
 
 // Checks if a given transport is supported by the registrySync operation.
@@ -44,11 +68,33 @@ func validregistrySyncTransport(transport types.ImageTransport) bool {
 		return true
 	case directory.Transport:
 		return true
+	case ocilayout.Transport:
+		return true
+	case ociarchive.Transport:
+		return true
+	case dockerarchive.Transport:
+		return true
 	}
 
 	return false
 }
 
+// splitPathAndTag splits a "path[:tag]" image locator, as used by the oci,
+// oci-archive and docker-archive transports, into its path and optional tag.
+func splitPathAndTag(sourceURL *url.URL) (string, string) {
+	locator := sourceURL.Opaque
+	if locator == "" {
+		locator = sourceURL.Path
+	}
+
+	idx := strings.LastIndex(locator, ":")
+	if idx == -1 {
+		return locator, ""
+	}
+
+	return locator[:idx], locator[idx+1:]
+}
+
 // Given a source URL and context, returns a list of tagged image references to
 // be used as registrySync source.
 func registrySyncFromURL(sourceURL *url.URL, sourceCtx *types.SystemContext) (repoDescriptor, error) {
@@ -88,6 +134,33 @@ func registrySyncFromURL(sourceURL *url.URL, sourceCtx *types.SystemContext) (re
 		if err != nil {
 			return repoDesc, err
 		}
+	case ocilayout.Transport:
+		dirPath, tag := splitPathAndTag(sourceURL)
+		var err error
+
+		repoDesc.DirBasePath = dirPath
+		repoDesc.TaggedImages, err = imagesToCopyFromOCILayout(dirPath, tag)
+		if err != nil {
+			return repoDesc, err
+		}
+	case ociarchive.Transport:
+		archivePath, tag := splitPathAndTag(sourceURL)
+		var err error
+
+		repoDesc.DirBasePath = archivePath
+		repoDesc.TaggedImages, err = imagesToCopyFromOCIArchive(archivePath, tag)
+		if err != nil {
+			return repoDesc, err
+		}
+	case dockerarchive.Transport:
+		archivePath, tag := splitPathAndTag(sourceURL)
+		var err error
+
+		repoDesc.DirBasePath = archivePath
+		repoDesc.TaggedImages, err = imagesToCopyFromDockerArchive(archivePath, tag)
+		if err != nil {
+			return repoDesc, err
+		}
 	}
 
 	if len(repoDesc.TaggedImages) == 0 {
@@ -97,36 +170,347 @@ func registrySyncFromURL(sourceURL *url.URL, sourceCtx *types.SystemContext) (re
 	return repoDesc, nil
 }
 
-type imageCollectChannel struct {
-	repoDesc repoDescriptor
-	err error
+// imagesToCopyFromOCILayout returns the tagged image references found in an
+// oci: layout directory. When tag is non-empty only that single ref is
+// returned, otherwise every ref present in the layout's index.json is used.
+func imagesToCopyFromOCILayout(dirPath string, tag string) ([]types.ImageReference, error) {
+	if tag != "" {
+		ref, err := ocilayout.NewReference(dirPath, tag)
+		if err != nil {
+			return nil, err
+		}
+		return []types.ImageReference{ref}, nil
+	}
+
+	refNames, err := ociLayoutRefNames(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []types.ImageReference
+	for _, refName := range refNames {
+		ref, err := ocilayout.NewReference(dirPath, refName)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// imagesToCopyFromOCIArchive returns the tagged image references found in an
+// oci-archive: tarball, which is just an oci layout packed into a tar file.
+func imagesToCopyFromOCIArchive(archivePath string, tag string) ([]types.ImageReference, error) {
+	if tag != "" {
+		ref, err := ociarchive.NewReference(archivePath, tag)
+		if err != nil {
+			return nil, err
+		}
+		return []types.ImageReference{ref}, nil
+	}
+
+	refNames, err := ociArchiveRefNames(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []types.ImageReference
+	for _, refName := range refNames {
+		ref, err := ociarchive.NewReference(archivePath, refName)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// imagesToCopyFromDockerArchive returns the tagged image references found in
+// a docker-archive: tarball. A single tarball can hold several name:tag
+// pairs, so when tag is empty every one of them is registrySync'd.
+func imagesToCopyFromDockerArchive(archivePath string, tag string) ([]types.ImageReference, error) {
+	if tag != "" {
+		ref, err := dockerarchive.ParseReference(fmt.Sprintf("%s:%s", archivePath, tag))
+		if err != nil {
+			return nil, err
+		}
+		return []types.ImageReference{ref}, nil
+	}
+
+	refNames, err := dockerArchiveRefNames(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []types.ImageReference
+	for _, refName := range refNames {
+		ref, err := dockerarchive.ParseReference(fmt.Sprintf("%s:%s", archivePath, refName))
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// Prefixes recognised inside an image's tag list in --source-yaml, letting a
+// server block say "mirror only what I need" instead of a static tag list.
+const (
+	tagSelectorRegexPrefix   = "tags-regex:"
+	tagSelectorSemverPrefix  = "tags-semver:"
+	tagSelectorLastPrefix    = "tags-last:"
+	tagSelectorExcludePrefix = "tags-exclude:"
+)
+
+// tagSelector is the set of tags-* filters mixed into a YAML image's tag
+// list, applied in order: regex, then semver, then exclude globs, then
+// keep the newest `last` by image config "created" (build) time, not
+// registry push time — a retagged-but-unchanged image (e.g. a floating
+// "latest") sorts by its original build time.
+type tagSelector struct {
+	regex   string
+	semver  string
+	last    int
+	exclude []string
+}
+
+func (sel tagSelector) empty() bool {
+	return sel.regex == "" && sel.semver == "" && sel.last == 0 && len(sel.exclude) == 0
+}
+
+// splitTagSelector pulls the tags-regex/-semver/-last/-exclude directives
+// out of a repo's tag list, leaving the plain literal tags (if any) behind.
+func splitTagSelector(tags []string) ([]string, tagSelector) {
+	var literalTags []string
+	var sel tagSelector
+
+	for _, t := range tags {
+		switch {
+		case strings.HasPrefix(t, tagSelectorRegexPrefix):
+			sel.regex = strings.TrimPrefix(t, tagSelectorRegexPrefix)
+		case strings.HasPrefix(t, tagSelectorSemverPrefix):
+			sel.semver = strings.TrimPrefix(t, tagSelectorSemverPrefix)
+		case strings.HasPrefix(t, tagSelectorLastPrefix):
+			n, err := strconv.Atoi(strings.TrimPrefix(t, tagSelectorLastPrefix))
+			if err != nil {
+				logrus.Errorf("Ignoring invalid %s%q", tagSelectorLastPrefix, strings.TrimPrefix(t, tagSelectorLastPrefix))
+				continue
+			}
+			sel.last = n
+		case strings.HasPrefix(t, tagSelectorExcludePrefix):
+			sel.exclude = append(sel.exclude, strings.TrimPrefix(t, tagSelectorExcludePrefix))
+		default:
+			literalTags = append(literalTags, t)
+		}
+	}
+
+	return literalTags, sel
+}
+
+// imagePlatformsPrefix lets a YAML image's tag list carry one
+// "platforms:os/arch[/variant][,os/arch[/variant]...]" entry, overriding the
+// global --platforms filter for just that image when one of its tags
+// resolves to a manifest list / OCI image index.
+const imagePlatformsPrefix = "platforms:"
+
+// splitPlatformsDirective pulls the optional platforms: directive (see
+// imagePlatformsPrefix) out of a repo's raw tag list, leaving the remaining
+// tags/selectors for splitTagSelector. A nil platforms return means no
+// per-image override was given, so the global --platforms filter applies.
+func splitPlatformsDirective(tags []string) ([]string, []string) {
+	var remaining []string
+	var platforms []string
+
+	for _, t := range tags {
+		if strings.HasPrefix(t, imagePlatformsPrefix) {
+			platforms = strings.Split(strings.TrimPrefix(t, imagePlatformsPrefix), ",")
+			continue
+		}
+		remaining = append(remaining, t)
+	}
+
+	return remaining, platforms
+}
+
+// registryTagNames lists every tag currently published for repoName.
+func registryTagNames(repoName string, sysCtx *types.SystemContext) ([]string, error) {
+	ref, err := docker.ParseReference(repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	return docker.GetRepositoryTags(context.Background(), sysCtx, ref)
+}
+
+// inspectTag resolves a single tag's ImageInspectInfo, consulting and
+// populating inspectCache so a tag that tags-last ends up keeping doesn't
+// get inspected a second time by copyImageTag's imageFetchManifest.
+func inspectTag(global *globalOptions, repoName string, tag string, sysCtx *types.SystemContext) (*types.ImageInspectInfo, error) {
+	ref, err := docker.ParseReference(fmt.Sprintf("%s:%s", repoName, tag))
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := transports.ImageName(ref)
+	if cached, ok := inspectCache.Load(cacheKey); ok {
+		return cached.(*types.ImageInspectInfo), nil
+	}
+
+	ctx, cancel := global.commandTimeoutContext()
+	defer cancel()
+
+	src, err := ref.NewImageSource(ctx, sysCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	img, err := image.FromSource(ctx, sysCtx, src)
+	if err != nil {
+		return nil, err
+	}
+	defer img.Close()
+
+	imgInspect, err := img.Inspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inspectCache.Store(cacheKey, imgInspect)
+	return imgInspect, nil
+}
+
+// applyTagSelector narrows candidates down to the tags matching sel,
+// applying filters in order: regex, semver (via Masterminds/semver),
+// exclude globs, then the newest `last` tags by image config "created".
+func applyTagSelector(global *globalOptions, sel tagSelector, candidates []string, repoName string, sysCtx *types.SystemContext) ([]string, error) {
+	tagNames := candidates
+
+	if sel.regex != "" {
+		re, err := regexp.Compile(sel.regex)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Error compiling tags-regex")
+		}
+
+		var filtered []string
+		for _, t := range tagNames {
+			if re.MatchString(t) {
+				filtered = append(filtered, t)
+			}
+		}
+		tagNames = filtered
+	}
+
+	if sel.semver != "" {
+		constraint, err := semver.NewConstraint(sel.semver)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Error parsing tags-semver constraint")
+		}
+
+		var filtered []string
+		for _, t := range tagNames {
+			v, err := semver.NewVersion(t)
+			if err != nil {
+				continue // not a semver-looking tag, quietly drop it rather than fail the whole sync
+			}
+			if constraint.Check(v) {
+				filtered = append(filtered, t)
+			}
+		}
+		tagNames = filtered
+	}
+
+	for _, glob := range sel.exclude {
+		var filtered []string
+		for _, t := range tagNames {
+			matched, err := path.Match(glob, t)
+			if err != nil {
+				return nil, errors.WithMessage(err, "Error matching tags-exclude glob")
+			}
+			if !matched {
+				filtered = append(filtered, t)
+			}
+		}
+		tagNames = filtered
+	}
+
+	if sel.last > 0 && len(tagNames) > sel.last {
+		created := make(map[string]time.Time, len(tagNames))
+		for _, t := range tagNames {
+			imgInspect, err := inspectTag(global, repoName, t, sysCtx)
+			if err != nil {
+				return nil, errors.WithMessage(err, fmt.Sprintf("Error inspecting tag %q to apply tags-last", t))
+			}
+			if imgInspect.Created != nil {
+				created[t] = *imgInspect.Created
+			}
+		}
+
+		sort.SliceStable(tagNames, func(i, j int) bool {
+			return created[tagNames[i]].After(created[tagNames[j]])
+		})
+
+		tagNames = tagNames[:sel.last]
+	}
+
+	return tagNames, nil
+}
+
+// repoSigningConfig carries a server block's per-registry signature-policy,
+// sign-by and sign-by-sigstore settings from the YAML source config through
+// to the repoDescriptor that run() uses to build that repo's PolicyContext
+// and copy.Options.
+type repoSigningConfig struct {
+	SignaturePolicyPath             string
+	SignBy                          string
+	SignBySigstorePrivateKeyFile    string
+	SignSigstorePrivateKeyPassphrase string
 }
 
-func registryCollectTagsForImage(imageName string, server string, tags []string, serverCtx *types.SystemContext, iCC chan imageCollectChannel) {
+// collectTagsForImage resolves the tagged image references to registrySync
+// for a single repo entry of the YAML source config. tags can be a static
+// list of literal tags, one or more tags-regex/-semver/-last/-exclude
+// selectors (see splitTagSelector), a "platforms:" directive (see
+// splitPlatformsDirective), or empty to mirror every tag.
+func collectTagsForImage(global *globalOptions, imageName string, server string, tags []string, serverCtx *types.SystemContext, signing repoSigningConfig) (repoDescriptor, error) {
 	repoName := fmt.Sprintf("//%s", path.Join(server, imageName))
 	logrus.WithFields(logrus.Fields{
 		"repo":     imageName,
 		"registry": server,
 	}).Info("Processing repo")
 
-	var err error
+	tags, platforms := splitPlatformsDirective(tags)
+	literalTags, sel := splitTagSelector(tags)
 
 	var sourceReferences []types.ImageReference
-	for _, tag := range tags {
-		source := fmt.Sprintf("%s:%s", repoName, tag)
 
-		imageRef, err := docker.ParseReference(source)
+	switch {
+	case !sel.empty():
+		allTags, err := registryTagNames(repoName, serverCtx)
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"tag": source,
-			}).Error("Error processing tag, skipping")
-			logrus.Errorf("Error getting image reference: %s", err)
-			continue
+			return repoDescriptor{}, err
 		}
-		sourceReferences = append(sourceReferences, imageRef)
-	}
 
-	if len(tags) == 0 {
+		filtered, err := applyTagSelector(global, sel, allTags, repoName, serverCtx)
+		if err != nil {
+			return repoDescriptor{}, err
+		}
+
+		for _, tag := range append(literalTags, filtered...) {
+			source := fmt.Sprintf("%s:%s", repoName, tag)
+
+			imageRef, err := docker.ParseReference(source)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"tag": source,
+				}).Error("Error processing tag, skipping")
+				logrus.Errorf("Error getting image reference: %s", err)
+				continue
+			}
+			sourceReferences = append(sourceReferences, imageRef)
+		}
+
+	case len(literalTags) == 0:
 		logrus.WithFields(logrus.Fields{
 			"repo":     imageName,
 			"registry": server,
@@ -134,20 +518,27 @@ func registryCollectTagsForImage(imageName string, server string, tags []string,
 
 		imageRef, err := docker.ParseReference(repoName)
 		if err != nil {
-			iCC <- imageCollectChannel{
-				repoDescriptor{},
-				err}
-
-			return
+			return repoDescriptor{}, err
 		}
 
 		sourceReferences, err = imagesToCopyFromRegistry(imageRef, repoName, serverCtx)
 		if err != nil {
-			iCC <- imageCollectChannel{
-				repoDescriptor{},
-				err}
+			return repoDescriptor{}, err
+		}
 
-			return
+	default:
+		for _, tag := range literalTags {
+			source := fmt.Sprintf("%s:%s", repoName, tag)
+
+			imageRef, err := docker.ParseReference(source)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"tag": source,
+				}).Error("Error processing tag, skipping")
+				logrus.Errorf("Error getting image reference: %s", err)
+				continue
+			}
+			sourceReferences = append(sourceReferences, imageRef)
 		}
 	}
 
@@ -157,14 +548,132 @@ func registryCollectTagsForImage(imageName string, server string, tags []string,
 			"registry": server,
 		}).Warnf("No tags to sync found")
 
-		err = errors.New("No tags to sync found")
+		return repoDescriptor{}, errors.New("No tags to sync found")
+	}
+
+	return repoDescriptor{
+		TaggedImages:                     sourceReferences,
+		Context:                          serverCtx,
+		Platforms:                        platforms,
+		SignaturePolicyPath:              signing.SignaturePolicyPath,
+		SignBy:                           signing.SignBy,
+		SignBySigstorePrivateKeyFile:     signing.SignBySigstorePrivateKeyFile,
+		SignSigstorePrivateKeyPassphrase: signing.SignSigstorePrivateKeyPassphrase,
+	}, nil
+}
+
+// ociIndex is the minimal subset of an OCI image index we need to enumerate
+// the refs stored in a layout directory or archive, and to look up a given
+// ref's manifest digest without reading the manifest blob itself.
+type ociIndex struct {
+	Manifests []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"manifests"`
+}
+
+// ociLayoutRefNames returns the "org.opencontainers.image.ref.name" values
+// found in dirPath/index.json.
+func ociLayoutRefNames(dirPath string) ([]string, error) {
+	raw, err := os.ReadFile(path.Join(dirPath, "index.json"))
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error reading oci index.json")
+	}
+
+	var index ociIndex
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, errors.WithMessage(err, "Error parsing oci index.json")
+	}
+
+	var refNames []string
+	for _, m := range index.Manifests {
+		if refName, ok := m.Annotations["org.opencontainers.image.ref.name"]; ok {
+			refNames = append(refNames, refName)
+		}
+	}
+	return refNames, nil
+}
+
+// ociArchiveRefNames is like ociLayoutRefNames but reads index.json out of a
+// tar archive instead of a plain directory.
+func ociArchiveRefNames(archivePath string) ([]string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error opening oci-archive")
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, "Error reading oci-archive")
+		}
+		if hdr.Name != "index.json" {
+			continue
+		}
+
+		var index ociIndex
+		if err := json.NewDecoder(tr).Decode(&index); err != nil {
+			return nil, errors.WithMessage(err, "Error parsing oci-archive index.json")
+		}
+
+		var refNames []string
+		for _, m := range index.Manifests {
+			if refName, ok := m.Annotations["org.opencontainers.image.ref.name"]; ok {
+				refNames = append(refNames, refName)
+			}
+		}
+		return refNames, nil
+	}
+
+	return nil, errors.New("index.json not found in oci-archive")
+}
+
+// dockerArchiveManifestEntry mirrors one entry of a docker-archive
+// manifest.json, which can list several RepoTags per tarball.
+type dockerArchiveManifestEntry struct {
+	RepoTags []string `json:"RepoTags"`
+}
+
+// dockerArchiveRefNames returns every "name:tag" found across all entries of
+// a docker-archive tarball's manifest.json.
+func dockerArchiveRefNames(archivePath string) ([]string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error opening docker-archive")
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, "Error reading docker-archive")
+		}
+		if hdr.Name != "manifest.json" {
+			continue
+		}
+
+		var entries []dockerArchiveManifestEntry
+		if err := json.NewDecoder(tr).Decode(&entries); err != nil {
+			return nil, errors.WithMessage(err, "Error parsing docker-archive manifest.json")
+		}
+
+		var refNames []string
+		for _, e := range entries {
+			refNames = append(refNames, e.RepoTags...)
+		}
+		return refNames, nil
 	}
 
-	iCC <- imageCollectChannel{
-		repoDescriptor{
-			TaggedImages: sourceReferences,
-			Context:      serverCtx},
-			err}
+	return nil, errors.New("manifest.json not found in docker-archive")
 }
 
 func fileExists(filename string) bool {
@@ -177,13 +686,21 @@ func fileExists(filename string) bool {
 
 // Given a yaml file and a source context, returns a list of repository descriptors,
 // each containing a list of tagged image references, to be used as registrySync source.
-func registrySyncFromYaml(yamlFile string, sourceCtx *types.SystemContext) (repoDescList []repoDescriptor, err error) {
+// Per-repo tag lookups run concurrently, bounded by maxParallelTagLookups; a
+// repo that fails to resolve is logged and skipped rather than aborting the
+// whole registry.
+func registrySyncFromYaml(global *globalOptions, yamlFile string, sourceCtx *types.SystemContext, maxParallelTagLookups int) (repoDescList []repoDescriptor, err error) {
 	cfg, err := newSourceConfig(yamlFile)
 
 	if err != nil {
 		return
 	}
 
+	if maxParallelTagLookups <= 0 {
+		maxParallelTagLookups = MAX_THREADS
+	}
+
+	var mu sync.Mutex
 	for server, serverCfg := range cfg {
 		if len(serverCfg.Images) == 0 {
 			logrus.WithFields(logrus.Fields{
@@ -192,9 +709,20 @@ func registrySyncFromYaml(yamlFile string, sourceCtx *types.SystemContext) (repo
 			continue
 		}
 
-		var cs = make([]chan imageCollectChannel, 0, MAX_THREADS)
+		g := new(errgroup.Group)
+		sem := make(chan struct{}, maxParallelTagLookups)
+
+		signing := repoSigningConfig{
+			SignaturePolicyPath:              serverCfg.SignaturePolicy,
+			SignBy:                           serverCfg.SignBy,
+			SignBySigstorePrivateKeyFile:     serverCfg.SignBySigstore.PrivateKeyFile,
+			SignSigstorePrivateKeyPassphrase: serverCfg.SignBySigstore.Passphrase,
+		}
+
 		for imageName, tags := range serverCfg.Images {
-			serverCtx := sourceCtx
+			imageName, tags := imageName, tags
+
+			serverCtx := *sourceCtx
 			// override ctx with per-server options
 			serverCtx.DockerCertPath = serverCfg.CertDir
 			serverCtx.DockerDaemonCertPath = serverCfg.CertDir
@@ -202,46 +730,32 @@ func registrySyncFromYaml(yamlFile string, sourceCtx *types.SystemContext) (repo
 			serverCtx.DockerInsecureSkipTLSVerify = types.NewOptionalBool(serverCfg.TLSVerify.skip)
 			serverCtx.DockerAuthConfig = &serverCfg.Credentials
 
-			cs = append(cs, make(chan imageCollectChannel))
-
-			go registryCollectTagsForImage(imageName, server, tags, serverCtx, cs[ len(cs) - 1])
-
-			for cap( cs ) == len( cs ) {
-				time.Sleep(10 * time.Millisecond)
-
-				for i := 0; i < len( cs ); i += 1 {
-					select {
-					case iCC := <-cs[ i ]:
-						cs[ i ] = cs[ len( cs ) - 1 ]
-						cs = cs[ :len( cs ) -1 ]
-						i -= 1
-
-						if iCC.err != nil {
-							logrus.WithFields(logrus.Fields{
-								"repo":     imageName, //FIXME: This shoud be fields in iCC as this is the last one appended
-								"registry": server,
-							}).Error("Error processing repo, skipping")
-							logrus.Error(err)
-							continue
-						}
-
-						repoDescList = append(repoDescList, iCC.repoDesc)
-					default:
-						continue
-					}
+			g.Go(func() error {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				repoDesc, err := collectTagsForImage(global, imageName, server, tags, &serverCtx, signing)
+				if err != nil {
+					logrus.WithFields(logrus.Fields{
+						"repo":     imageName,
+						"registry": server,
+					}).Error("Error processing repo, skipping")
+					logrus.Error(err)
+					return nil
 				}
-			}
-		}
 
-		for i := 0; i < len( cs ); i += 1 {
-			iCC := <-cs[ i ]
+				mu.Lock()
+				repoDescList = append(repoDescList, repoDesc)
+				mu.Unlock()
 
-			if iCC.err != nil {
-				continue
-			}
-
-			repoDescList = append(repoDescList, iCC.repoDesc)
+				return nil
+			})
 		}
+
+		// Errors are already logged and swallowed above, so this can only
+		// return nil; kept so a future fail-fast mode for tag lookups has
+		// somewhere to plug in.
+		_ = g.Wait()
 	}
 
 	return
@@ -250,6 +764,7 @@ func registrySyncFromYaml(yamlFile string, sourceCtx *types.SystemContext) (repo
 type copyImageTagChannel struct {
 	done bool
 	err error
+	diff *syncDiffEntry
 }
 
 type copyImageTagOptions struct {
@@ -262,9 +777,342 @@ type copyImageTagOptions struct {
 	ctx context.Context
 	policyContext *signature.PolicyContext
 	options copy.Options
+	destWriter *dockerarchive.Writer
+	platforms []string
+	dryRun bool
 	cITC chan copyImageTagChannel
 }
 
+// taggedNameFromSource derives the repo:tag of a tagged source image
+// reference, so it can be reused to build a same-name ref against a
+// different transport (e.g. a shared docker-archive or oci destination).
+// docker:// and docker-archive: sources carry a real reference.NamedTagged
+// internally, which DockerReference() returns directly; string-splitting
+// transports.ImageName (as this used to do) breaks for docker-archive,
+// whose locator embeds a second "repo:tag" after the archive path, e.g.
+// "docker-archive:/path/to/x.tar:myrepo/image:v1".
+func taggedNameFromSource(imageRef types.ImageReference) (dockerreference.NamedTagged, error) {
+	if named := imageRef.DockerReference(); named != nil {
+		tagged, ok := named.(dockerreference.NamedTagged)
+		if !ok {
+			return nil, errors.Errorf("image %q has no tag", transports.ImageName(imageRef))
+		}
+		return tagged, nil
+	}
+
+	// oci: and oci-archive: sources have no docker-style reference; their
+	// ref is just the "org.opencontainers.image.ref.name" tag they were
+	// constructed with (see ociLayoutRefNames/ociArchiveRefNames). Use
+	// StringWithinTransport, which unlike transports.ImageName isn't
+	// prefixed with the transport name, and take only the base name of its
+	// directory/archive path as the repo so the path itself can't leak in.
+	withinTransport := imageRef.StringWithinTransport()
+	idx := strings.LastIndex(withinTransport, ":")
+	if idx == -1 {
+		return nil, errors.Errorf("Cannot determine tag of image %q", transports.ImageName(imageRef))
+	}
+
+	repo := strings.TrimLeft(path.Base(withinTransport[:idx]), "/")
+	tag := withinTransport[idx+1:]
+
+	named, err := dockerreference.ParseNormalizedNamed(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return dockerreference.WithTag(named, tag)
+}
+
+// repoPolicyContext returns the signature.PolicyContext to use for srcRepo:
+// the process-wide context built from --policy, unless the server's YAML
+// block set its own signature-policy fragment, in which case that fragment
+// is loaded standalone and used for this repo only. Callers must only
+// Destroy() the returned context when it isn't the global one they were
+// handed.
+func repoPolicyContext(global *signature.PolicyContext, srcRepo repoDescriptor) (*signature.PolicyContext, error) {
+	if srcRepo.SignaturePolicyPath == "" {
+		return global, nil
+	}
+
+	policy, err := signature.NewPolicyFromFile(srcRepo.SignaturePolicyPath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error loading per-registry signature-policy")
+	}
+
+	return signature.NewPolicyContext(policy)
+}
+
+// copyTagError carries the {registry, repo, tag} of an image that failed to
+// registrySync, so run() can report exactly which tag failed instead of
+// relying on whatever the worker pool happened to process last (see the
+// FIXME registrySyncFromYaml used to have, for the same class of bug).
+type copyTagError struct {
+	Registry string
+	Repo     string
+	Tag      string
+	Err      error
+}
+
+func (e *copyTagError) Error() string {
+	return fmt.Sprintf("%s/%s:%s: %v", e.Registry, e.Repo, e.Tag, e.Err)
+}
+
+func (e *copyTagError) Unwrap() error {
+	return e.Err
+}
+
+// wrapTagError annotates err with the registry/repo/tag of opts.imageRef.
+func (opts copyImageTagOptions) wrapTagError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	registry := opts.imageRef.Transport().Name()
+	repo := transports.ImageName(opts.imageRef)
+	tag := ""
+
+	if named, nerr := taggedNameFromSource(opts.imageRef); nerr == nil {
+		registry = dockerreference.Domain(named)
+		repo = dockerreference.Path(named)
+		tag = named.Tag()
+	}
+
+	return &copyTagError{Registry: registry, Repo: repo, Tag: tag, Err: err}
+}
+
+// syncDiffEntry is one line of --dry-run output: what registry-sync would
+// have done for a single (source ref, dest ref) pair.
+type syncDiffEntry struct {
+	Src       string `json:"src"`
+	Dst       string `json:"dst"`
+	Action    string `json:"action"` // "create", "update" or "skip"
+	SrcDigest string `json:"src_digest,omitempty"`
+	DstDigest string `json:"dst_digest,omitempty"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+}
+
+// destManifestDigest fetches the manifest digest currently present at
+// destRef, without copying anything: via the registry for docker://
+// destinations, by reading manifest.json straight off disk for dir:
+// destinations, or by resolving the matching entry of index.json for oci:
+// destinations (an OCI layout has no top-level manifest.json of its own).
+// A non-nil error means the destination doesn't exist yet.
+func destManifestDigest(ctx context.Context, destRef types.ImageReference, destSysCtx *types.SystemContext) (digest.Digest, error) {
+	destIN := transports.ImageName(destRef)
+
+	if destIN[:3] == "dir" {
+		manifestPath := destIN[4:] + "/manifest.json"
+
+		if !fileExists(manifestPath) {
+			return "", errors.New("destination does not exist yet")
+		}
+
+		raw, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return "", err
+		}
+		return manifest.Digest(raw)
+	}
+
+	if strings.HasPrefix(destIN, "oci:") {
+		ociPath := destIN[4:]
+		idx := strings.LastIndex(ociPath, ":")
+		if idx == -1 {
+			return "", errors.Errorf("Cannot determine oci: destination tag from %q", destIN)
+		}
+		dirPath, tag := ociPath[:idx], ociPath[idx+1:]
+
+		indexPath := path.Join(dirPath, "index.json")
+		if !fileExists(indexPath) {
+			return "", errors.New("destination does not exist yet")
+		}
+
+		raw, err := os.ReadFile(indexPath)
+		if err != nil {
+			return "", err
+		}
+
+		var index ociIndex
+		if err := json.Unmarshal(raw, &index); err != nil {
+			return "", errors.WithMessage(err, "Error parsing oci index.json")
+		}
+
+		for _, m := range index.Manifests {
+			if m.Annotations["org.opencontainers.image.ref.name"] == tag {
+				return digest.Parse(m.Digest)
+			}
+		}
+		return "", errors.New("destination does not exist yet")
+	}
+
+	destSrc, err := destRef.NewImageSource(ctx, destSysCtx)
+	if err != nil {
+		return "", err
+	}
+	defer destSrc.Close()
+
+	blob, _, err := destSrc.GetManifest(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	return manifest.Digest(blob)
+}
+
+// planCopy compares the source and destination manifest digests for
+// --dry-run and reports what copyImageTag would have done, without copying
+// anything.
+func planCopy(opts copyImageTagOptions, destRef types.ImageReference) (syncDiffEntry, error) {
+	entry := syncDiffEntry{
+		Src: transports.ImageName(opts.imageRef),
+		Dst: transports.ImageName(destRef),
+	}
+
+	srcSource, err := opts.imageRef.NewImageSource(opts.ctx, opts.srcRepo.Context)
+	if err != nil {
+		return entry, err
+	}
+	defer srcSource.Close()
+
+	srcBlob, _, err := srcSource.GetManifest(opts.ctx, nil)
+	if err != nil {
+		return entry, err
+	}
+
+	srcDigest, err := manifest.Digest(srcBlob)
+	if err != nil {
+		return entry, err
+	}
+	entry.SrcDigest = srcDigest.String()
+
+	var m registrySyncManifest
+	if jerr := json.Unmarshal(srcBlob, &m); jerr == nil {
+		entry.SizeBytes = int64(m.Config.Size)
+		for _, layer := range m.Layers {
+			entry.SizeBytes += int64(layer.Size)
+		}
+	}
+
+	dstDigest, err := destManifestDigest(opts.ctx, destRef, opts.options.DestinationCtx)
+	switch {
+	case err != nil:
+		entry.Action = "create"
+	case dstDigest == srcDigest:
+		entry.Action = "skip"
+	default:
+		entry.Action = "update"
+		entry.DstDigest = dstDigest.String()
+	}
+
+	return entry, nil
+}
+
+// buildArchiveDestination builds the destination reference for destinations
+// that hold several tagged images behind a single path: a shared
+// docker-archive Writer (one tarball, many name:tag entries) or an oci:
+// layout directory (one index.json, many refs), as opposed to 'dir:' which
+// gets one directory per tag.
+func buildArchiveDestination(opts copyImageTagOptions) (types.ImageReference, error) {
+	if opts.destWriter != nil {
+		named, err := taggedNameFromSource(opts.imageRef)
+		if err != nil {
+			return nil, err
+		}
+		return opts.destWriter.NewReference(named)
+	}
+
+	switch transports.Get(opts.destinationURL.Scheme) {
+	case ocilayout.Transport:
+		dirPath, _ := splitPathAndTag(opts.destinationURL)
+		named, err := taggedNameFromSource(opts.imageRef)
+		if err != nil {
+			return nil, err
+		}
+		return ocilayout.NewReference(dirPath, named.Tag())
+	}
+
+	return nil, nil
+}
+
+// copySigstoreSignatureTag looks for a cosign/sigstore signature published
+// alongside opts.imageRef as the conventional "sha256-<digest>.sig" tag, and
+// if one is present, copies it to the same destination repository under the
+// same tag. A missing signature tag is not an error: most images simply
+// aren't signed that way, so its absence is silently ignored and only copy
+// failures for a signature tag that does exist are reported to the caller.
+// Only docker:// sources can carry one: the sigstore tag convention lives on
+// the source registry, while dir:/oci:/oci-archive:/docker-archive: sources
+// have no registry repo to probe, and probing the name taggedNameFromSource
+// derives for them would fire a bogus lookup at the default registry.
+func copySigstoreSignatureTag(opts copyImageTagOptions) error {
+	if opts.imageRef.Transport() != docker.Transport {
+		return nil
+	}
+
+	srcSource, err := opts.imageRef.NewImageSource(opts.ctx, opts.srcRepo.Context)
+	if err != nil {
+		return err
+	}
+	manifestBlob, _, err := srcSource.GetManifest(opts.ctx, nil)
+	closeErr := srcSource.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	srcDigest, err := manifest.Digest(manifestBlob)
+	if err != nil {
+		return err
+	}
+
+	named, err := taggedNameFromSource(opts.imageRef)
+	if err != nil {
+		return err
+	}
+
+	sigTag := strings.Replace(srcDigest.String(), ":", "-", 1) + ".sig"
+	sigName, err := dockerreference.WithTag(named, sigTag)
+	if err != nil {
+		return err
+	}
+
+	sigSrcRef, err := docker.NewReference(sigName)
+	if err != nil {
+		return err
+	}
+
+	probeSource, err := sigSrcRef.NewImageSource(opts.ctx, opts.srcRepo.Context)
+	if err != nil {
+		// No sigstore signature tag published for this image: nothing to propagate.
+		return nil
+	}
+	probeSource.Close()
+
+	sigOpts := opts
+	sigOpts.imageRef = sigSrcRef
+
+	sigDestRef, err := buildArchiveDestination(sigOpts)
+	if err != nil {
+		return err
+	}
+	if sigDestRef == nil {
+		sigDestRef, err = buildFinalDestination(sigSrcRef, opts.destinationURL, opts.srcRepo.DirBasePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	sigCopyOptions := copy.Options{
+		DestinationCtx: opts.options.DestinationCtx,
+		SourceCtx:      opts.options.SourceCtx,
+		ReportWriter:   opts.options.ReportWriter,
+	}
+
+	_, err = copy.Image(opts.ctx, opts.policyContext, sigDestRef, sigSrcRef, &sigCopyOptions)
+	return err
+}
+
 type registrySyncManifestConfig struct {
 		MediaType string
 		Size int
@@ -279,6 +1127,11 @@ type registrySyncManifest struct {
 }
 
 func imageFetchManifest( opts copyImageTagOptions ) ( *types.ImageInspectInfo, error ) {
+	cacheKey := transports.ImageName( opts.imageRef )
+	if cached, ok := inspectCache.Load( cacheKey ); ok {
+		return cached.(*types.ImageInspectInfo), nil
+	}
+
 	ctx, cancel := opts.global.commandTimeoutContext()
 	defer cancel()
 
@@ -300,22 +1153,133 @@ func imageFetchManifest( opts copyImageTagOptions ) ( *types.ImageInspectInfo, e
 		return imgInspect, err
 	}
 
+	inspectCache.Store( cacheKey, imgInspect )
+
 	return imgInspect, err
 }
 
+// platformMatches reports whether an instance's platform matches one of the
+// "os/arch[/variant]" filters requested via --platforms.
+func platformMatches(platform *manifest.Schema2PlatformSpec, wanted []string) bool {
+	for _, want := range wanted {
+		parts := strings.SplitN(want, "/", 3)
+		if len(parts) < 2 {
+			continue
+		}
+
+		if platform.OS != parts[0] || platform.Architecture != parts[1] {
+			continue
+		}
+		if len(parts) == 3 && platform.Variant != parts[2] {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// imageListInstances inspects opts.imageRef and, if it is a manifest list /
+// OCI image index, returns the instance digests matching opts.platforms.
+// ok is false when opts.imageRef is a plain single-arch image, in which case
+// the caller should fall back to the existing single-image handling.
+// A nil, but ok, digest slice means "keep every instance" (no --platforms
+// filter was requested); it is an error, rather than falling back to "keep
+// every instance", for a requested --platforms filter to match zero of
+// them.
+func imageListInstances(opts copyImageTagOptions) (instances []digest.Digest, ok bool, err error) {
+	ctx, cancel := opts.global.commandTimeoutContext()
+	defer cancel()
+
+	rawSource, err := opts.imageRef.NewImageSource(ctx, opts.srcRepo.Context)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rawSource.Close()
+
+	manifestBlob, mimeType, err := rawSource.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !manifest.MIMETypeIsMultiImage(mimeType) {
+		return nil, false, nil
+	}
+
+	list, err := manifest.ListFromBlob(manifestBlob, mimeType)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(opts.platforms) == 0 {
+		return nil, true, nil
+	}
+
+	for _, instanceDigest := range list.Instances() {
+		platform, err := list.Instance(instanceDigest)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if platformMatches(&platform, opts.platforms) {
+			instances = append(instances, instanceDigest)
+		}
+	}
+
+	if len(instances) == 0 {
+		return nil, false, errors.Errorf("--platforms %q matched no instances of %q", strings.Join(opts.platforms, ","), transports.ImageName(opts.imageRef))
+	}
+
+	return instances, true, nil
+}
+
 func copyImageTag(opts copyImageTagOptions) {
 	retryCount := 0
+	var tagErr error
 	Retry: for {
-		destRef, err := buildFinalDestination(opts.imageRef, opts.destinationURL, opts.srcRepo.DirBasePath)
+		destRef, err := buildArchiveDestination(opts)
 		if err != nil {
-			opts.cITC <-copyImageTagChannel{false, err}
+			opts.cITC <-copyImageTagChannel{false, opts.wrapTagError(err), nil}
+			return
+		}
+		if destRef == nil {
+			destRef, err = buildFinalDestination(opts.imageRef, opts.destinationURL, opts.srcRepo.DirBasePath)
+			if err != nil {
+				opts.cITC <-copyImageTagChannel{false, opts.wrapTagError(err), nil}
+				return
+			}
+		}
+
+		if opts.dryRun {
+			// Must run before the dir:-already-exists block below: that
+			// block can break the Retry loop for an up-to-date tag before
+			// ever reaching dry-run handling, which left --dry-run silently
+			// emitting no line at all (not even "skip") for unchanged dir:
+			// destinations.
+			diff, err := planCopy(opts, destRef)
+			if err != nil {
+				tagErr = opts.wrapTagError(err)
+				logrus.Error(tagErr)
+				break
+			}
+
+			diffJSON, err := json.Marshal(diff)
+			if err != nil {
+				tagErr = opts.wrapTagError(err)
+				break
+			}
+			fmt.Fprintln(opts.options.ReportWriter, string(diffJSON))
+
+			opts.cITC <-copyImageTagChannel{true, nil, &diff}
 			return
 		}
 
 		imgInspect, err := imageFetchManifest( opts )
 
 		if err != nil {
-			logrus.Error( err )
+			tagErr = opts.wrapTagError(err)
+			logrus.Error( tagErr )
 			break
 		}
 
@@ -365,7 +1329,25 @@ func copyImageTag(opts copyImageTagOptions) {
 			}
 		}
 
-		if len( opts.global.overrideArch ) > 0 && len( imgInspect.Architecture ) > 0 {
+		instances, isList, err := imageListInstances(opts)
+		if err != nil {
+			tagErr = opts.wrapTagError(err)
+			logrus.Error(tagErr)
+			break
+		}
+
+		if isList {
+			// A manifest list / OCI image index: copy every matching
+			// platform instance, or the whole list when --platforms
+			// wasn't given. The single-arch overrideArch shortcut below
+			// only applies to plain, non-list manifests.
+			if instances != nil {
+				opts.options.ImageListSelection = copy.CopySpecificImages
+				opts.options.Instances = instances
+			} else {
+				opts.options.ImageListSelection = copy.CopyAllImages
+			}
+		} else if len( opts.global.overrideArch ) > 0 && len( imgInspect.Architecture ) > 0 {
 			if opts.global.overrideArch != imgInspect.Architecture {
 				// if we are not operating on the correct Architecture do no make a copy
 				break
@@ -379,18 +1361,26 @@ func copyImageTag(opts copyImageTagOptions) {
 
 		_, err = copy.Image(opts.ctx, opts.policyContext, destRef, opts.imageRef, &opts.options)
 		if err != nil {
-			logrus.Error(errors.WithMessage(err, fmt.Sprintf("Error copying tag '%s'; Try: %d", transports.ImageName(opts.imageRef), retryCount + 1)))
+			tagErr = opts.wrapTagError(errors.WithMessage(err, fmt.Sprintf("Try: %d", retryCount+1)))
+			logrus.Error(tagErr)
 
 			if retryCount < 3 {
 				retryCount += 1
 				continue Retry
 			}
+
+			break
 		}
 
+		if sigErr := copySigstoreSignatureTag(opts); sigErr != nil {
+			logrus.Warnf("Could not propagate sigstore signature for %q: %v", transports.ImageName(opts.imageRef), sigErr)
+		}
+
+		tagErr = nil
 		break
 	}
 
-	opts.cITC <-copyImageTagChannel{true, nil}
+	opts.cITC <-copyImageTagChannel{tagErr == nil, tagErr, nil}
 }
 
 func (opts *registrySyncOptions) run(args []string, stdout io.Writer) error {
@@ -419,10 +1409,15 @@ func (opts *registrySyncOptions) run(args []string, stdout io.Writer) error {
 	}
 	sourceArg := args[0]
 
+	var platformFilters []string
+	if opts.platforms != "" {
+		platformFilters = strings.Split(opts.platforms, ",")
+	}
+
 	var srcRepoList []repoDescriptor
 
 	if opts.sourceYaml {
-		srcRepoList, err = registrySyncFromYaml(sourceArg, sourceCtx)
+		srcRepoList, err = registrySyncFromYaml(opts.global, sourceArg, sourceCtx, opts.maxParallelTagLookups)
 		if err != nil {
 			return err
 		}
@@ -447,11 +1442,41 @@ func (opts *registrySyncOptions) run(args []string, stdout io.Writer) error {
 	ctx, cancel := opts.global.commandTimeoutContext()
 	defer cancel()
 
-	// I want a pool of "processes" to handle a set of tags in parallel
-	var cs = make([]chan copyImageTagChannel, 0, MAX_THREADS)
+	// A docker-archive destination batches every tag from every source repo
+	// into a single tarball, so the Writer is opened once here and shared
+	// by all of the per-tag goroutines below instead of being owned by
+	// copyImageTag.
+	var destWriter *dockerarchive.Writer
+	if transports.Get(destinationURL.Scheme) == dockerarchive.Transport {
+		archivePath, _ := splitPathAndTag(destinationURL)
+		destWriter, err = dockerarchive.NewWriter(destinationCtx, archivePath)
+		if err != nil {
+			return errors.WithMessage(err, "Error opening docker-archive destination")
+		}
+		defer destWriter.Close()
+	}
+
+	// A bounded pool of goroutines to handle tag copies in parallel: a
+	// semaphore caps concurrency at maxParallelCopies, and the errgroup
+	// both waits for every copy and, in fail-fast mode, cancels the rest on
+	// the first error. Outside of fail-fast, failures are collected into
+	// failures and reported as one aggregated error at the end.
+	maxParallelCopies := opts.maxParallelCopies
+	if maxParallelCopies <= 0 {
+		maxParallelCopies = MAX_THREADS
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, maxParallelCopies)
 
+	var mu sync.Mutex
 	var imgCounter int
+	var failures []error
+	var diffs []syncDiffEntry
+
 	for _, srcRepo := range srcRepoList {
+		srcRepo := srcRepo
+
 		options := copy.Options{
 			RemoveSignatures: opts.removeSignatures,
 			SignBy:           opts.signByFingerprint,
@@ -460,47 +1485,110 @@ func (opts *registrySyncOptions) run(args []string, stdout io.Writer) error {
 			SourceCtx:        srcRepo.Context,
 		}
 
-		opts.srcImage.credsOption.present = true
-		opts.srcImage.credsOption.value = srcRepo.Context.DockerAuthConfig.Username + ":" + srcRepo.Context.DockerAuthConfig.Password
+		// A YAML image's own "platforms:" directive (see
+		// splitPlatformsDirective) takes precedence over the global
+		// --platforms flag for that image only.
+		repoPlatforms := platformFilters
+		if len(srcRepo.Platforms) > 0 {
+			repoPlatforms = srcRepo.Platforms
+		}
+
+		// A server block's sign-by/sign-by-sigstore YAML settings take
+		// precedence over the global --sign-by flag for that registry only.
+		if srcRepo.SignBy != "" {
+			options.SignBy = srcRepo.SignBy
+		}
+		if srcRepo.SignBySigstorePrivateKeyFile != "" {
+			options.SignBySigstorePrivateKeyFile = srcRepo.SignBySigstorePrivateKeyFile
+			options.SignSigstorePrivateKeyPassphrase = []byte(srcRepo.SignSigstorePrivateKeyPassphrase)
+		}
+
+		repoPC, err := repoPolicyContext(policyContext, srcRepo)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("Error loading signature-policy for %q", transports.ImageName(srcRepo.TaggedImages[0])))
+		}
+		if repoPC != policyContext {
+			defer repoPC.Destroy()
+		}
+
+		// A shallow copy per repo, not a mutation of the shared *imageOptions:
+		// repos now run concurrently (no drain between them, see the errgroup
+		// scheduler above), so two repos' goroutines could otherwise race to
+		// overwrite opts.srcImage.credsOption and send each other's creds to
+		// the wrong registry.
+		srcImageOpts := *opts.srcImage
+		srcImageOpts.credsOption.present = true
+		srcImageOpts.credsOption.value = srcRepo.Context.DockerAuthConfig.Username + ":" + srcRepo.Context.DockerAuthConfig.Password
 
 		for counter, ref := range srcRepo.TaggedImages {
-			cs = append(cs, make(chan copyImageTagChannel))
-			options := copyImageTagOptions {counter, opts.global, ref, destinationURL, opts.srcImage, srcRepo, ctx, policyContext, options, cs[ len(cs) - 1]}
+			counter, ref := counter, ref
+
+			g.Go(func() error {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-gctx.Done():
+					return gctx.Err()
+				}
 
-			go copyImageTag(options)
+				cITC := make(chan copyImageTagChannel)
+				tagOptions := copyImageTagOptions{counter, opts.global, ref, destinationURL, &srcImageOpts, srcRepo, ctx, repoPC, options, destWriter, repoPlatforms, opts.dryRun, cITC}
 
-			for cap( cs ) == len( cs ) {
-				time.Sleep(10 * time.Millisecond)
+				go copyImageTag(tagOptions)
+				result := <-cITC
 
-				for i := 0; i < len( cs ); i += 1 {
-					select {
-					case cITC := <-cs[ i ]: // TODO: need to handle errors
-						cs[ i ] = cs[ len( cs ) - 1 ]
-						cs = cs[ :len( cs ) -1 ]
-						i -= 1
+				mu.Lock()
+				defer mu.Unlock()
+				imgCounter++
 
-						if cITC.err != nil {}
-					default:
-						continue
+				if result.diff != nil {
+					diffs = append(diffs, *result.diff)
+				}
+
+				if result.err != nil {
+					if opts.failFast {
+						return result.err
 					}
+					failures = append(failures, result.err)
 				}
-			}
-			imgCounter++
-		}
 
+				return nil
+			})
+		}
+	}
 
-		// Drop Channels to 0 before continuing
-		for i := 0; i < len( cs ); i += 1 {
-			cITC := <-cs[ i ]
-			cs[ i ] = cs[ len( cs ) - 1 ]
-			cs = cs[ :len( cs ) -1 ]
-			i -= 1
+	if err := g.Wait(); err != nil {
+		return errors.WithMessage(err, "Aborting registry-sync, --fail-fast")
+	}
 
-			if cITC.err != nil {}
+	if opts.dryRun {
+		var toAdd, toUpdate, toSkip int
+		var totalBytes int64
+		for _, d := range diffs {
+			switch d.Action {
+			case "create":
+				toAdd++
+				totalBytes += d.SizeBytes
+			case "update":
+				toUpdate++
+				totalBytes += d.SizeBytes
+			default:
+				toSkip++
+			}
 		}
+
+		logrus.Infof("dry-run summary: %d to add, %d to update, %d unchanged, %d bytes to transfer", toAdd, toUpdate, toSkip, totalBytes)
+	} else {
+		logrus.Infof("registry-synced %d images from %d sources", imgCounter, len(srcRepoList))
 	}
 
-	logrus.Infof("registry-synced %d images from %d sources", imgCounter, len(srcRepoList))
+	if len(failures) > 0 {
+		msgs := make([]string, len(failures))
+		for i, e := range failures {
+			msgs[i] = e.Error()
+		}
+		return errors.Errorf("%d/%d tags failed to registry-sync:\n%s", len(failures), imgCounter, strings.Join(msgs, "\n"))
+	}
 
 	return nil
 }
@@ -552,8 +1640,10 @@ func registrySyncCmd(global *globalOptions) cli.Command {
 	to populate also registries running inside of air-gapped environments.
 
 	SOURCE can be either a repository hosted on a container registry
-	(eg: docker://registry.example.com/busybox) or a local directory
-	(eg: dir:/media/usb/).
+	(eg: docker://registry.example.com/busybox), a local directory
+	(eg: dir:/media/usb/), an oci layout directory (eg: oci:/media/usb/layout),
+	or an oci-archive/docker-archive tarball (eg: oci-archive:/media/usb/busybox.tar,
+	docker-archive:/media/usb/busybox.tar).
 
 	If --source-yaml is specified, then SOURCE points to a YAML file with
 	a list of source images from different container registries
@@ -562,12 +1652,48 @@ func registrySyncCmd(global *globalOptions) cli.Command {
 	When syncing from a repository where no tags are specified, skopeo
 	registry-sync will copy all the tags contained in that repository.
 
+	When a tag resolves to a manifest list or OCI image index, every platform
+	it contains is copied by default; pass --platforms to keep only a subset
+	(eg: --platforms linux/amd64,linux/arm64/v8). With --source-yaml, an
+	image's tag list can instead carry its own "platforms:<OS/ARCH[/VARIANT],...>"
+	entry, overriding --platforms for just that image.
+
+	With --source-yaml, an image's tag list can mix literal tags with
+	selectors instead of naming every tag: "tags-regex:<pattern>",
+	"tags-semver:<constraint>" (eg: ">=1.4.0 <2.0.0"), "tags-last:<N>" (keep
+	the N tags with the newest image build time, not registry push time: a
+	retagged-but-unchanged tag sorts by its original build time), and
+	"tags-exclude:<glob>". Selectors are applied in that order against the
+	full tag list fetched from the registry.
+
+	Tag copies and, with --source-yaml, per-repo tag lookups run in parallel;
+	use --max-parallel-copies and --max-parallel-tag-lookups to bound that.
+	By default a failing tag is logged and the rest of the sync continues,
+	with all failures reported together at the end; pass --fail-fast to
+	abort on the first one instead.
+
+	--dry-run reports what would change without copying anything: one JSON
+	line per tag with {src, dst, action, src_digest, dst_digest,
+	size_bytes}, where action is "create", "update" or "skip", followed by a
+	final summary of images to add/update and total bytes to transfer.
+
+	With --source-yaml, a server block may also set "signature-policy:" to
+	a signature verification policy file used only for that registry's
+	images instead of the one loaded via --policy, and "sign-by:" /
+	"sign-by-sigstore:" (private-key-file, passphrase) to sign destination
+	images with a GPG key or a sigstore private key as they are copied.
+	Any cosign/sigstore signature published alongside a source tag as a
+	"sha256-<digest>.sig" tag is copied to the destination as well.
+
 	DESTINATION can be either a container registry
-	(eg: docker://my-registry.local.lan) or a local directory
-	(eg: dir:/media/usb).
+	(eg: docker://my-registry.local.lan), a local directory
+	(eg: dir:/media/usb), an oci layout directory (eg: oci:/media/usb/layout),
+	or a docker-archive tarball (eg: docker-archive:/media/usb/mirror.tar).
 
 	When DESTINATION is a local directory, one directory per 'image:tag' is going
-	to be created.
+	to be created. When DESTINATION is an oci: layout or a docker-archive:
+	tarball, every synced 'image:tag' is instead written into the same
+	layout directory or tarball.
 	`),
 		ArgsUsage: "[--source-yaml] SOURCE DESTINATION",
 		Action:    commandAction(opts.run),
@@ -588,6 +1714,33 @@ func registrySyncCmd(global *globalOptions) cli.Command {
 				Usage:       "Interpret SOURCE as a YAML file with a list of images from different container registries",
 				Destination: &opts.sourceYaml,
 			},
+			cli.StringFlag{
+				Name:        "platforms",
+				Usage:       "Comma-separated `OS/ARCH[/VARIANT]` list to keep when SOURCE is a manifest list or OCI image index; defaults to copying every platform",
+				Destination: &opts.platforms,
+			},
+			cli.IntFlag{
+				Name:        "max-parallel-copies",
+				Usage:       "Maximum number of tag copies to run in parallel",
+				Value:       MAX_THREADS,
+				Destination: &opts.maxParallelCopies,
+			},
+			cli.IntFlag{
+				Name:        "max-parallel-tag-lookups",
+				Usage:       "Maximum number of per-repo tag lookups to run in parallel when reading --source-yaml",
+				Value:       MAX_THREADS,
+				Destination: &opts.maxParallelTagLookups,
+			},
+			cli.BoolFlag{
+				Name:        "fail-fast",
+				Usage:       "Abort on the first tag that fails to registry-sync instead of aggregating all failures into a final error",
+				Destination: &opts.failFast,
+			},
+			cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "Report what registry-sync would do, as one JSON line per tag, without copying anything",
+				Destination: &opts.dryRun,
+			},
 		}, sharedFlags...), srcFlags...), destFlags...),
 	}
 }